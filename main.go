@@ -21,12 +21,20 @@ func main() {
 		debug       bool
 		showIfaces  bool
 		showVersion bool
+		ipv4        bool
+		ipv6        bool
+		filterPath  string
+		configPath  string
 	)
 
 	flag.StringVar(&interfaces, "i", "", "Comma-separated list of interface names (e.g., eth0,eth1)")
 	flag.BoolVar(&debug, "d", false, "Enable debug logging")
 	flag.BoolVar(&showIfaces, "l", false, "List available network interfaces")
 	flag.BoolVar(&showVersion, "v", false, "Show version information")
+	flag.BoolVar(&ipv4, "4", true, "Reflect IPv4 mDNS (224.0.0.251)")
+	flag.BoolVar(&ipv6, "6", true, "Reflect IPv6 mDNS (ff02::fb)")
+	flag.StringVar(&filterPath, "filter", "", "Path to a YAML file of per-service allow/deny rules")
+	flag.StringVar(&configPath, "c", "", "Path to a YAML config file (overrides -i, -d, -4, -6, -filter); reloaded on SIGHUP")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "mDNS Reflector %s - Reflect mDNS packets between network interfaces\n\n", Version)
@@ -50,23 +58,44 @@ func main() {
 		return
 	}
 
-	if interfaces == "" {
-		fmt.Fprintf(os.Stderr, "Error: No interfaces specified\n\n")
-		fmt.Fprintf(os.Stderr, "Use -i flag\n\n")
-		flag.Usage()
-		os.Exit(1)
-	}
-
-	ifaceNames := strings.Split(interfaces, ",")
-	for i := range ifaceNames {
-		ifaceNames[i] = strings.TrimSpace(ifaceNames[i])
-	}
-
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 	log.Printf("Starting mDNS Reflector...")
-	log.Printf("Interfaces: %v", ifaceNames)
 
-	reflector, err := r.NewReflector(ifaceNames, debug)
+	var reflector *r.Reflector
+	var err error
+
+	if configPath != "" {
+		cfg, loadErr := r.LoadConfig(configPath)
+		if loadErr != nil {
+			log.Fatalf("Failed to load config: %v", loadErr)
+		}
+		log.Printf("Interfaces: %v", cfg.Interfaces)
+
+		reflector, err = r.NewReflectorFromConfig(cfg)
+	} else {
+		if interfaces == "" {
+			fmt.Fprintf(os.Stderr, "Error: No interfaces specified\n\n")
+			fmt.Fprintf(os.Stderr, "Use -i flag\n\n")
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		ifaceNames := strings.Split(interfaces, ",")
+		for i := range ifaceNames {
+			ifaceNames[i] = strings.TrimSpace(ifaceNames[i])
+		}
+		log.Printf("Interfaces: %v", ifaceNames)
+
+		var filterRules []r.Rule
+		if filterPath != "" {
+			filterRules, err = r.LoadFilterRules(filterPath)
+			if err != nil {
+				log.Fatalf("Failed to load filter rules: %v", err)
+			}
+		}
+
+		reflector, err = r.NewReflector(ifaceNames, debug, ipv4, ipv6, filterRules)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create reflector: %v", err)
 	}
@@ -76,10 +105,30 @@ func main() {
 	}
 
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	for sig := range sigChan {
+		if sig == syscall.SIGHUP {
+			if configPath == "" {
+				log.Printf("Received SIGHUP but no -c config file was given, ignoring")
+				continue
+			}
+
+			log.Printf("Received SIGHUP, reloading config from %s", configPath)
+			cfg, err := r.LoadConfig(configPath)
+			if err != nil {
+				log.Printf("Failed to reload config: %v", err)
+				continue
+			}
+			if err := reflector.Reload(cfg); err != nil {
+				log.Printf("Failed to apply reloaded config: %v", err)
+			}
+			continue
+		}
 
-	sig := <-sigChan
-	log.Printf("Received signal %v, shutting down...", sig)
+		log.Printf("Received signal %v, shutting down...", sig)
+		break
+	}
 
 	reflector.Stop()
 	log.Printf("mDNS Reflector stopped")
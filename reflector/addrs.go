@@ -0,0 +1,41 @@
+package reflector
+
+import "net"
+
+// unicastIPs returns the non-multicast IP addresses configured on iface.
+// It's used to recognize and drop our own reflected packets by source
+// address, independent of the FNV dedup cache.
+func unicastIPs(iface *net.Interface) ([]net.IP, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		var ip net.IP
+		switch a := addr.(type) {
+		case *net.IPNet:
+			ip = a.IP
+		case *net.IPAddr:
+			ip = a.IP
+		default:
+			continue
+		}
+		if ip.IsMulticast() {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
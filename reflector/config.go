@@ -0,0 +1,46 @@
+package reflector
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the on-disk representation of a reflector configuration, as
+// loaded via the -c flag. It mirrors the command-line flags so that a
+// config file and one-shot flag invocation stay interchangeable.
+type Config struct {
+	Interfaces []string `yaml:"interfaces"`
+	Debug      bool     `yaml:"debug"`
+	IPv4       *bool    `yaml:"ipv4"`
+	IPv6       *bool    `yaml:"ipv6"`
+	Filters    []Rule   `yaml:"filters"`
+}
+
+// IPv4Enabled reports whether IPv4 reflection is enabled, defaulting to
+// true when unset.
+func (c *Config) IPv4Enabled() bool {
+	return c.IPv4 == nil || *c.IPv4
+}
+
+// IPv6Enabled reports whether IPv6 reflection is enabled, defaulting to
+// true when unset.
+func (c *Config) IPv6Enabled() bool {
+	return c.IPv6 == nil || *c.IPv6
+}
+
+// LoadConfig reads and parses a YAML config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
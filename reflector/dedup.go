@@ -23,9 +23,9 @@ func newDedupCache() *dedupCache {
 	}
 }
 
-func (c *dedupCache) isDuplicate(srcIface string, packet []byte) bool {
+func (c *dedupCache) isDuplicate(src connKey, packet []byte) bool {
 	h := fnv.New64a()
-	h.Write([]byte(srcIface))
+	h.Write([]byte(src.String()))
 	h.Write(packet)
 	key := h.Sum64()
 
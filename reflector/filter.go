@@ -0,0 +1,186 @@
+package reflector
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule describes a single mDNS forwarding policy. A rule applies to
+// reflections from From to To (either left empty matches any interface)
+// and, if Direction is set, only to queries or only to responses. Allow
+// and Deny list service/record patterns such as "_airplay._tcp.local.";
+// Types, if set, restricts matching to those DNS record types.
+type Rule struct {
+	From      string   `yaml:"from,omitempty"`
+	To        string   `yaml:"to,omitempty"`
+	Direction string   `yaml:"direction,omitempty"`
+	Types     []string `yaml:"types,omitempty"`
+	Allow     []string `yaml:"allow,omitempty"`
+	Deny      []string `yaml:"deny,omitempty"`
+}
+
+func (rule Rule) applies(from, to, direction string) bool {
+	if rule.From != "" && rule.From != from {
+		return false
+	}
+	if rule.To != "" && rule.To != to {
+		return false
+	}
+	if rule.Direction != "" && rule.Direction != direction {
+		return false
+	}
+	return true
+}
+
+func (rule Rule) matchesType(typ string) bool {
+	if len(rule.Types) == 0 {
+		return true
+	}
+	for _, t := range rule.Types {
+		if strings.EqualFold(t, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Rule) matchesAny(patterns []string, recs []serviceRecord) bool {
+	for _, rec := range recs {
+		if !rule.matchesType(rec.typ) {
+			continue
+		}
+		for _, pattern := range patterns {
+			if matchesService(pattern, rec.name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesService(pattern, name string) bool {
+	return strings.EqualFold(strings.TrimSuffix(pattern, "."), strings.TrimSuffix(name, "."))
+}
+
+// serviceRecord is a (name, record type) pair extracted from a DNS
+// message's Question or Answer section for the purposes of filtering.
+type serviceRecord struct {
+	name string
+	typ  string
+}
+
+func extractRecords(msg *dnsmessage.Message) []serviceRecord {
+	recs := make([]serviceRecord, 0, len(msg.Questions)+len(msg.Answers))
+
+	for _, q := range msg.Questions {
+		recs = append(recs, serviceRecord{name: q.Name.String(), typ: recordTypeName(q.Type)})
+	}
+	for _, a := range msg.Answers {
+		recs = append(recs, serviceRecord{name: a.Header.Name.String(), typ: recordTypeName(a.Header.Type)})
+	}
+
+	return recs
+}
+
+func recordTypeName(t dnsmessage.Type) string {
+	switch t {
+	case dnsmessage.TypeA:
+		return "A"
+	case dnsmessage.TypeAAAA:
+		return "AAAA"
+	case dnsmessage.TypePTR:
+		return "PTR"
+	case dnsmessage.TypeSRV:
+		return "SRV"
+	case dnsmessage.TypeTXT:
+		return "TXT"
+	case dnsmessage.TypeCNAME:
+		return "CNAME"
+	default:
+		return fmt.Sprintf("TYPE%d", uint16(t))
+	}
+}
+
+// Filter evaluates mDNS packets against a set of Rules. Rules can be
+// swapped atomically, which lets a config reload take effect without
+// disrupting in-flight reflections.
+type Filter struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+func NewFilter(rules []Rule) *Filter {
+	return &Filter{rules: rules}
+}
+
+func (f *Filter) SetRules(rules []Rule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = rules
+}
+
+func (f *Filter) HasRules() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.rules) > 0
+}
+
+// Allow reports whether a packet reflected from the from interface to the
+// to interface should be forwarded. A deny match on an applicable rule
+// always wins; otherwise, if any applicable rule carries an allow list,
+// the packet must match one of them.
+func (f *Filter) Allow(from, to string, msg *dnsmessage.Message) bool {
+	f.mu.RLock()
+	rules := f.rules
+	f.mu.RUnlock()
+
+	if len(rules) == 0 {
+		return true
+	}
+
+	direction := "query"
+	if msg.Header.Response {
+		direction = "response"
+	}
+	recs := extractRecords(msg)
+
+	for _, rule := range rules {
+		if rule.applies(from, to, direction) && len(rule.Deny) > 0 && rule.matchesAny(rule.Deny, recs) {
+			return false
+		}
+	}
+
+	haveAllow := false
+	for _, rule := range rules {
+		if !rule.applies(from, to, direction) || len(rule.Allow) == 0 {
+			continue
+		}
+		haveAllow = true
+		if rule.matchesAny(rule.Allow, recs) {
+			return true
+		}
+	}
+
+	return !haveAllow
+}
+
+// LoadFilterRules reads a YAML file containing a list of Rules, as passed
+// via the -filter flag.
+func LoadFilterRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter rules %s: %w", path, err)
+	}
+
+	var rules []Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse filter rules %s: %w", path, err)
+	}
+
+	return rules, nil
+}
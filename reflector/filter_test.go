@@ -0,0 +1,99 @@
+package reflector
+
+import (
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func buildQuery(t *testing.T, name string, typ dnsmessage.Type) *dnsmessage.Message {
+	t.Helper()
+
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		t.Fatalf("NewName(%q): %v", name, err)
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := b.StartQuestions(); err != nil {
+		t.Fatalf("StartQuestions: %v", err)
+	}
+	if err := b.Question(dnsmessage.Question{Name: n, Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		t.Fatalf("Question: %v", err)
+	}
+
+	buf, err := b.Finish()
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf); err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	return &msg
+}
+
+func TestFilterAllowNoRules(t *testing.T) {
+	f := NewFilter(nil)
+	msg := buildQuery(t, "_airplay._tcp.local.", dnsmessage.TypePTR)
+
+	if !f.Allow("eth0", "wlan0", msg) {
+		t.Errorf("expected packet to be allowed when no rules are configured")
+	}
+}
+
+func TestFilterAllowListRestrictsService(t *testing.T) {
+	f := NewFilter([]Rule{
+		{From: "eth0", To: "wlan0", Allow: []string{"_airplay._tcp.local.", "_raop._tcp.local."}},
+	})
+
+	allowed := buildQuery(t, "_airplay._tcp.local.", dnsmessage.TypePTR)
+	if !f.Allow("eth0", "wlan0", allowed) {
+		t.Errorf("expected _airplay service to be allowed")
+	}
+
+	denied := buildQuery(t, "_googlecast._tcp.local.", dnsmessage.TypePTR)
+	if f.Allow("eth0", "wlan0", denied) {
+		t.Errorf("expected _googlecast service to be dropped by the allow list")
+	}
+}
+
+func TestFilterAllowListUnaffectedByInterfacePair(t *testing.T) {
+	f := NewFilter([]Rule{
+		{From: "eth0", To: "wlan0", Allow: []string{"_airplay._tcp.local."}},
+	})
+
+	msg := buildQuery(t, "_googlecast._tcp.local.", dnsmessage.TypePTR)
+	if !f.Allow("eth0", "guest0", msg) {
+		t.Errorf("expected rule scoped to eth0->wlan0 to not affect eth0->guest0")
+	}
+}
+
+func TestFilterDenyOverridesAllow(t *testing.T) {
+	f := NewFilter([]Rule{
+		{From: "eth0", To: "wlan0", Allow: []string{"_airplay._tcp.local."}},
+		{From: "eth0", To: "wlan0", Deny: []string{"_airplay._tcp.local."}, Types: []string{"PTR"}},
+	})
+
+	msg := buildQuery(t, "_airplay._tcp.local.", dnsmessage.TypePTR)
+	if f.Allow("eth0", "wlan0", msg) {
+		t.Errorf("expected deny rule to take priority over allow rule")
+	}
+}
+
+func TestFilterTypeRestriction(t *testing.T) {
+	f := NewFilter([]Rule{
+		{From: "eth0", To: "wlan0", Types: []string{"SRV"}, Allow: []string{"_airplay._tcp.local."}},
+	})
+
+	ptr := buildQuery(t, "_airplay._tcp.local.", dnsmessage.TypePTR)
+	if f.Allow("eth0", "wlan0", ptr) {
+		t.Errorf("expected PTR query to be dropped when rule only allows SRV")
+	}
+
+	srv := buildQuery(t, "_airplay._tcp.local.", dnsmessage.TypeSRV)
+	if !f.Allow("eth0", "wlan0", srv) {
+		t.Errorf("expected SRV query to be allowed")
+	}
+}
@@ -8,23 +8,37 @@ import (
 	"net"
 	"sync"
 	"time"
-)
 
-var mdnsIPv4Addr = net.ParseIP("224.0.0.251")
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
 
 const (
 	mdnsPort     = 5353
 	maxPacketLen = 9000
 
+	// requiredTTL is the IP TTL/hop limit mDNS packets must carry, both
+	// inbound and outbound, per RFC 6762 section 11.
+	requiredTTL = 255
+
 	shutdownTimeout = 5 * time.Second
 )
 
 type Reflector struct {
-	interfaces []*net.Interface
-	conns      map[string]*net.UDPConn
-	mu         sync.RWMutex
-	dedup      *dedupCache
-	debug      bool
+	interfaces     []*net.Interface
+	ifaceByIdx     map[int]*net.Interface
+	ifaceUnicastIP map[string][]net.IP
+	ipv4           bool
+	ipv6           bool
+
+	pc4 *ipv4.PacketConn
+	pc6 *ipv6.PacketConn
+
+	mu     sync.RWMutex
+	dedup  *dedupCache
+	filter *Filter
+	debug  bool
 
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -32,62 +46,119 @@ type Reflector struct {
 	shutdownWg sync.WaitGroup
 }
 
-func NewReflector(ifaceNames []string, debug bool) (*Reflector, error) {
+func NewReflector(ifaceNames []string, debug, enableIPv4, enableIPv6 bool, filterRules []Rule) (*Reflector, error) {
+	if !enableIPv4 && !enableIPv6 {
+		return nil, fmt.Errorf("at least one of IPv4 or IPv6 must be enabled")
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
+	interfaces, err := resolveInterfaces(ifaceNames)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	r := &Reflector{
-		conns:  make(map[string]*net.UDPConn),
+		ipv4:   enableIPv4,
+		ipv6:   enableIPv6,
 		dedup:  newDedupCache(),
+		filter: NewFilter(filterRules),
 		debug:  debug,
 		ctx:    ctx,
 		cancel: cancel,
 	}
+	r.setInterfaces(interfaces)
 
-	for _, name := range ifaceNames {
+	return r, nil
+}
+
+// NewReflectorFromConfig builds a Reflector from a loaded Config, as used
+// when the -c flag is given instead of individual command-line flags.
+func NewReflectorFromConfig(cfg *Config) (*Reflector, error) {
+	return NewReflector(cfg.Interfaces, cfg.Debug, cfg.IPv4Enabled(), cfg.IPv6Enabled(), cfg.Filters)
+}
+
+// resolveInterfaces looks up each named interface and validates that it is
+// up and multicast-capable.
+func resolveInterfaces(names []string) ([]*net.Interface, error) {
+	interfaces := make([]*net.Interface, 0, len(names))
+
+	for _, name := range names {
 		iface, err := net.InterfaceByName(name)
 		if err != nil {
-			cancel()
 			return nil, fmt.Errorf("interface %s not found: %w", name, err)
 		}
 
 		if iface.Flags&net.FlagUp == 0 {
-			cancel()
 			return nil, fmt.Errorf("interface %s is down", name)
 		}
 		if iface.Flags&net.FlagMulticast == 0 {
-			cancel()
 			return nil, fmt.Errorf("interface %s does not support multicast", name)
 		}
 
-		r.interfaces = append(r.interfaces, iface)
+		interfaces = append(interfaces, iface)
 	}
 
-	if len(r.interfaces) < 2 {
-		cancel()
-		return nil, fmt.Errorf("at least 2 interfaces are required, got %d", len(r.interfaces))
+	if len(interfaces) < 2 {
+		return nil, fmt.Errorf("at least 2 interfaces are required, got %d", len(interfaces))
 	}
 
-	return r, nil
+	return interfaces, nil
+}
+
+// setInterfaces replaces the reflector's interface set and rebuilds the
+// lookup maps used for reflection and IfIndex attribution. Callers holding
+// r.mu must already have it locked when the reflector is running.
+func (r *Reflector) setInterfaces(interfaces []*net.Interface) {
+	r.interfaces = interfaces
+	r.ifaceByIdx = make(map[int]*net.Interface, len(interfaces))
+	r.ifaceUnicastIP = make(map[string][]net.IP, len(interfaces))
+	for _, iface := range interfaces {
+		r.ifaceByIdx[iface.Index] = iface
+
+		ips, err := unicastIPs(iface)
+		if err != nil {
+			log.Printf("Failed to read addresses for %s: %v", iface.Name, err)
+			continue
+		}
+		r.ifaceUnicastIP[iface.Name] = ips
+	}
 }
 
 func (r *Reflector) Start() error {
-	for _, iface := range r.interfaces {
-		conn, err := r.joinMulticast(iface)
+	if r.ipv4 {
+		pc, err := r.openIPv4()
 		if err != nil {
 			r.Stop()
-			return fmt.Errorf("failed to join multicast on %s: %w", iface.Name, err)
+			return fmt.Errorf("failed to open IPv4 socket: %w", err)
 		}
-		r.conns[iface.Name] = conn
-		log.Printf("Joined mDNS multicast group on interface: %s", iface.Name)
+		r.pc4 = pc
+		log.Printf("Joined mDNS multicast group (ipv4) on %d interfaces", len(r.interfaces))
+	}
+
+	if r.ipv6 {
+		pc, err := r.openIPv6()
+		if err != nil {
+			r.Stop()
+			return fmt.Errorf("failed to open IPv6 socket: %w", err)
+		}
+		r.pc6 = pc
+		log.Printf("Joined mDNS multicast group (ipv6) on %d interfaces", len(r.interfaces))
 	}
 
 	r.wg.Go(func() {
 		r.dedup.runCleanup(r.ctx)
 	})
 
-	for _, iface := range r.interfaces {
+	if r.pc4 != nil {
+		r.wg.Go(func() {
+			r.receiveLoopV4()
+		})
+	}
+	if r.pc6 != nil {
 		r.wg.Go(func() {
-			r.receiveLoop(iface)
+			r.receiveLoopV6()
 		})
 	}
 
@@ -95,31 +166,69 @@ func (r *Reflector) Start() error {
 	return nil
 }
 
-func (r *Reflector) joinMulticast(iface *net.Interface) (*net.UDPConn, error) {
-	addr := &net.UDPAddr{
-		IP:   mdnsIPv4Addr,
-		Port: mdnsPort,
-	}
+func (r *Reflector) receiveLoopV4() {
+	r.mu.RLock()
+	pc := r.pc4
+	r.mu.RUnlock()
 
-	conn, err := net.ListenMulticastUDP("udp4", iface, addr)
-	if err != nil {
-		return nil, err
+	if pc == nil {
+		return
 	}
 
-	if err := conn.SetReadBuffer(maxPacketLen); err != nil {
-		conn.Close()
-		return nil, err
-	}
+	buf := make([]byte, maxPacketLen)
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			if r.debug {
+				log.Printf("IPv4 receive loop stopping due to context cancellation")
+			}
+			return
+		default:
+		}
+
+		pc.SetReadDeadline(time.Now().Add(1 * time.Second))
+
+		n, cm, srcAddr, err := pc.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("Error reading IPv4 mDNS socket: %v", err)
+			}
+			return
+		}
+
+		if n == 0 || cm == nil {
+			continue
+		}
+
+		r.mu.RLock()
+		iface, ok := r.ifaceByIdx[cm.IfIndex]
+		r.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if cm.TTL != requiredTTL {
+			if r.debug {
+				log.Printf("Dropping packet on %s with TTL %d (expected %d)", iface.Name, cm.TTL, requiredTTL)
+			}
+			continue
+		}
 
-	return conn, nil
+		r.handlePacket(iface, connKey{iface: iface.Name, family: familyIPv4}, buf[:n], srcAddr)
+	}
 }
 
-func (r *Reflector) receiveLoop(iface *net.Interface) {
+func (r *Reflector) receiveLoopV6() {
 	r.mu.RLock()
-	conn := r.conns[iface.Name]
+	pc := r.pc6
 	r.mu.RUnlock()
 
-	if conn == nil {
+	if pc == nil {
 		return
 	}
 
@@ -129,60 +238,96 @@ func (r *Reflector) receiveLoop(iface *net.Interface) {
 		select {
 		case <-r.ctx.Done():
 			if r.debug {
-				log.Printf("Receive loop for %s stopping due to context cancellation", iface.Name)
+				log.Printf("IPv6 receive loop stopping due to context cancellation")
 			}
 			return
 		default:
 		}
 
-		conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+		pc.SetReadDeadline(time.Now().Add(1 * time.Second))
 
-		n, srcAddr, err := conn.ReadFromUDP(buf)
+		n, cm, srcAddr, err := pc.ReadFrom(buf)
 		if err != nil {
 			var netErr net.Error
 			if errors.As(err, &netErr) && netErr.Timeout() {
 				continue
 			}
 			if !errors.Is(err, net.ErrClosed) {
-				log.Printf("Error reading from %s: %v", iface.Name, err)
+				log.Printf("Error reading IPv6 mDNS socket: %v", err)
 			}
 			return
 		}
 
-		if n == 0 {
+		if n == 0 || cm == nil {
 			continue
 		}
 
-		select {
-		case <-r.ctx.Done():
+		r.mu.RLock()
+		iface, ok := r.ifaceByIdx[cm.IfIndex]
+		r.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if cm.HopLimit != requiredTTL {
 			if r.debug {
-				log.Printf("Dropping packet on %s due to shutdown", iface.Name)
+				log.Printf("Dropping packet on %s with hop limit %d (expected %d)", iface.Name, cm.HopLimit, requiredTTL)
 			}
-			return
-		default:
+			continue
 		}
 
-		packet := make([]byte, n)
-		copy(packet, buf[:n])
+		r.handlePacket(iface, connKey{iface: iface.Name, family: familyIPv6}, buf[:n], srcAddr)
+	}
+}
 
-		if r.dedup.isDuplicate(iface.Name, packet) {
+// handlePacket runs the anti-loop and dedup checks common to both address
+// families before handing the packet off to reflect. iface is the
+// interface the packet actually arrived on, resolved from the socket
+// control message's IfIndex.
+func (r *Reflector) handlePacket(iface *net.Interface, key connKey, data []byte, srcAddr net.Addr) {
+	select {
+	case <-r.ctx.Done():
+		return
+	default:
+	}
+
+	if udpAddr, ok := srcAddr.(*net.UDPAddr); ok {
+		r.mu.RLock()
+		ownIPs := r.ifaceUnicastIP[iface.Name]
+		r.mu.RUnlock()
+
+		if containsIP(ownIPs, udpAddr.IP) {
 			if r.debug {
-				log.Printf("Suppressed duplicate %d bytes on %s from %s", n, iface.Name, srcAddr.String())
+				log.Printf("Dropping packet on %s from our own address %s", key, udpAddr.IP)
 			}
-			continue
+			return
 		}
+	}
+
+	packet := make([]byte, len(data))
+	copy(packet, data)
 
+	if r.dedup.isDuplicate(key, packet) {
 		if r.debug {
-			log.Printf("Received %d bytes on %s from %s", n, iface.Name, srcAddr.String())
+			log.Printf("Suppressed duplicate %d bytes on %s from %s", len(packet), key, srcAddr)
 		}
+		return
+	}
 
-		r.shutdownWg.Go(func() {
-			r.reflect(iface.Name, packet)
-		})
+	if r.debug {
+		log.Printf("Received %d bytes on %s from %s", len(packet), key, srcAddr)
 	}
+
+	r.shutdownWg.Go(func() {
+		r.reflect(key, packet)
+	})
 }
 
-func (r *Reflector) reflect(srcIface string, packet []byte) {
+// reflect forwards packet to every configured interface other than the
+// source, restricted to the source's address family so IPv4 and IPv6
+// traffic never cross, pinning egress to each destination interface via
+// its IfIndex.
+func (r *Reflector) reflect(src connKey, packet []byte) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -192,29 +337,58 @@ func (r *Reflector) reflect(srcIface string, packet []byte) {
 	default:
 	}
 
-	dstAddr := &net.UDPAddr{
-		IP:   mdnsIPv4Addr,
-		Port: mdnsPort,
+	dstAddr := destAddr(src.family)
+
+	var msg *dnsmessage.Message
+	if r.filter.HasRules() {
+		var m dnsmessage.Message
+		if err := m.Unpack(packet); err != nil {
+			if r.debug {
+				log.Printf("Dropping packet from %s that failed to parse as DNS with filter rules configured: %v", src, err)
+			}
+			return
+		}
+		msg = &m
 	}
 
-	for ifaceName, conn := range r.conns {
-		if ifaceName == srcIface {
+	for _, iface := range r.interfaces {
+		if iface.Name == src.iface {
+			continue
+		}
+
+		if msg != nil && !r.filter.Allow(src.iface, iface.Name, msg) {
+			if r.debug {
+				log.Printf("Filtered packet from %s to %s", src, iface.Name)
+			}
 			continue
 		}
 
-		_, err := conn.WriteToUDP(packet, dstAddr)
+		var err error
+		switch src.family {
+		case familyIPv4:
+			if r.pc4 == nil {
+				continue
+			}
+			_, err = r.pc4.WriteTo(packet, &ipv4.ControlMessage{IfIndex: iface.Index}, dstAddr)
+		case familyIPv6:
+			if r.pc6 == nil {
+				continue
+			}
+			_, err = r.pc6.WriteTo(packet, &ipv6.ControlMessage{IfIndex: iface.Index}, dstAddr)
+		}
+
 		if err != nil {
 			select {
 			case <-r.ctx.Done():
 				return
 			default:
-				log.Printf("Error reflecting to %s: %v", ifaceName, err)
+				log.Printf("Error reflecting to %s/%s: %v", iface.Name, src.family, err)
 			}
 			continue
 		}
 
 		if r.debug {
-			log.Printf("Reflected %d bytes from %s to %s", len(packet), srcIface, ifaceName)
+			log.Printf("Reflected %d bytes from %s to %s/%s", len(packet), src, iface.Name, src.family)
 		}
 	}
 }
@@ -238,13 +412,16 @@ func (r *Reflector) Stop() {
 	}
 
 	r.mu.Lock()
-	for name, conn := range r.conns {
-		if conn != nil {
-			conn.Close()
-			log.Printf("Closed connection on interface: %s", name)
-		}
+	if r.pc4 != nil {
+		r.pc4.Close()
+		log.Printf("Closed IPv4 mDNS socket")
+		r.pc4 = nil
+	}
+	if r.pc6 != nil {
+		r.pc6.Close()
+		log.Printf("Closed IPv6 mDNS socket")
+		r.pc6 = nil
 	}
-	r.conns = make(map[string]*net.UDPConn)
 	r.mu.Unlock()
 
 	done = make(chan struct{})
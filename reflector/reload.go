@@ -0,0 +1,107 @@
+package reflector
+
+import (
+	"fmt"
+	"log"
+	"net"
+)
+
+// Reload applies a new Config to a running Reflector without restarting
+// it. Interfaces present in both the old and new sets keep their existing
+// multicast group membership so in-flight mDNS conversations aren't
+// disrupted; removed interfaces leave the group and added ones join it.
+// Filter rules are swapped atomically. IPv4/IPv6 enablement is fixed at
+// Start and cannot be changed by a reload.
+func (r *Reflector) Reload(cfg *Config) error {
+	interfaces, err := resolveInterfaces(cfg.Interfaces)
+	if err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added, removed := diffInterfaces(r.interfaces, interfaces)
+
+	// joined4/joined6 track groups successfully joined so far in this call,
+	// so a later failure can roll them back rather than leaving the socket
+	// with membership for an interface that never makes it into
+	// r.interfaces/r.ifaceByIdx.
+	var joined4, joined6 []*net.Interface
+	rollbackJoins := func() {
+		for _, iface := range joined4 {
+			if err := r.pc4.LeaveGroup(iface, &net.UDPAddr{IP: mdnsIPv4Addr}); err != nil {
+				log.Printf("Reload: rollback failed to leave IPv4 group on %s: %v", iface.Name, err)
+			}
+		}
+		for _, iface := range joined6 {
+			if err := r.pc6.LeaveGroup(iface, &net.UDPAddr{IP: mdnsIPv6Addr}); err != nil {
+				log.Printf("Reload: rollback failed to leave IPv6 group on %s: %v", iface.Name, err)
+			}
+		}
+	}
+
+	for _, iface := range added {
+		if r.pc4 != nil {
+			if err := r.pc4.JoinGroup(iface, &net.UDPAddr{IP: mdnsIPv4Addr}); err != nil {
+				rollbackJoins()
+				return fmt.Errorf("reload: failed to join IPv4 group on %s: %w", iface.Name, err)
+			}
+			joined4 = append(joined4, iface)
+		}
+		if r.pc6 != nil {
+			if err := r.pc6.JoinGroup(iface, &net.UDPAddr{IP: mdnsIPv6Addr}); err != nil {
+				rollbackJoins()
+				return fmt.Errorf("reload: failed to join IPv6 group on %s: %w", iface.Name, err)
+			}
+			joined6 = append(joined6, iface)
+		}
+		log.Printf("Reload: joined multicast group on new interface %s", iface.Name)
+	}
+
+	for _, iface := range removed {
+		if r.pc4 != nil {
+			if err := r.pc4.LeaveGroup(iface, &net.UDPAddr{IP: mdnsIPv4Addr}); err != nil {
+				log.Printf("Reload: failed to leave IPv4 group on %s: %v", iface.Name, err)
+			}
+		}
+		if r.pc6 != nil {
+			if err := r.pc6.LeaveGroup(iface, &net.UDPAddr{IP: mdnsIPv6Addr}); err != nil {
+				log.Printf("Reload: failed to leave IPv6 group on %s: %v", iface.Name, err)
+			}
+		}
+		log.Printf("Reload: left multicast group on removed interface %s", iface.Name)
+	}
+
+	r.setInterfaces(interfaces)
+	r.filter.SetRules(cfg.Filters)
+
+	log.Printf("Reload: now running with %d interfaces", len(interfaces))
+	return nil
+}
+
+// diffInterfaces returns the interfaces present in next but not cur
+// (added) and those present in cur but not next (removed), keyed by name.
+func diffInterfaces(cur, next []*net.Interface) (added, removed []*net.Interface) {
+	curByName := make(map[string]*net.Interface, len(cur))
+	for _, iface := range cur {
+		curByName[iface.Name] = iface
+	}
+	nextByName := make(map[string]*net.Interface, len(next))
+	for _, iface := range next {
+		nextByName[iface.Name] = iface
+	}
+
+	for name, iface := range nextByName {
+		if _, ok := curByName[name]; !ok {
+			added = append(added, iface)
+		}
+	}
+	for name, iface := range curByName {
+		if _, ok := nextByName[name]; !ok {
+			removed = append(removed, iface)
+		}
+	}
+
+	return added, removed
+}
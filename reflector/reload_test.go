@@ -0,0 +1,139 @@
+package reflector
+
+import (
+	"net"
+	"os/exec"
+	"testing"
+)
+
+// setupVethPair creates a veth pair for use as a virtual interface in
+// Reload tests and returns the host-side interface. It skips the test if
+// the sandbox can't create network devices (no CAP_NET_ADMIN, no "ip"
+// binary, or the kernel's veth driver is unavailable).
+func setupVethPair(t *testing.T, name string) *net.Interface {
+	t.Helper()
+
+	peer := name + "p"
+	if out, err := exec.Command("ip", "link", "add", name, "type", "veth", "peer", "name", peer).CombinedOutput(); err != nil {
+		t.Skipf("cannot create veth pair %s/%s: %v: %s", name, peer, err, out)
+	}
+	t.Cleanup(func() {
+		exec.Command("ip", "link", "del", name).Run()
+	})
+
+	if out, err := exec.Command("ip", "link", "set", name, "up").CombinedOutput(); err != nil {
+		t.Skipf("cannot bring up %s: %v: %s", name, err, out)
+	}
+
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		t.Skipf("cannot look up %s after creating it: %v", name, err)
+	}
+	if iface.Flags&net.FlagMulticast == 0 {
+		t.Skipf("%s does not support multicast in this sandbox", name)
+	}
+
+	return iface
+}
+
+func namesOf(ifaces []*net.Interface) []string {
+	names := make([]string, len(ifaces))
+	for i, iface := range ifaces {
+		names[i] = iface.Name
+	}
+	return names
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiffInterfacesAddAndRemove(t *testing.T) {
+	eth0 := &net.Interface{Index: 1, Name: "eth0"}
+	wlan0 := &net.Interface{Index: 2, Name: "wlan0"}
+	guest0 := &net.Interface{Index: 3, Name: "guest0"}
+
+	added, removed := diffInterfaces([]*net.Interface{eth0, wlan0}, []*net.Interface{eth0, guest0})
+
+	if len(added) != 1 || !containsName(namesOf(added), "guest0") {
+		t.Errorf("expected guest0 to be added, got %v", namesOf(added))
+	}
+	if len(removed) != 1 || !containsName(namesOf(removed), "wlan0") {
+		t.Errorf("expected wlan0 to be removed, got %v", namesOf(removed))
+	}
+}
+
+func TestDiffInterfacesNoOp(t *testing.T) {
+	eth0 := &net.Interface{Index: 1, Name: "eth0"}
+	wlan0 := &net.Interface{Index: 2, Name: "wlan0"}
+
+	added, removed := diffInterfaces([]*net.Interface{eth0, wlan0}, []*net.Interface{eth0, wlan0})
+
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes, got added=%v removed=%v", namesOf(added), namesOf(removed))
+	}
+}
+
+// TestReflectorReload exercises add, remove and no-op reloads against a
+// real Reflector running over a set of virtual interfaces, checking that
+// Reload succeeds (a failed group join is returned as an error) and updates
+// the live interface set rather than just the pure diffInterfaces helper.
+// Group membership itself is not inspected.
+func TestReflectorReload(t *testing.T) {
+	a := setupVethPair(t, "vr0a")
+	b := setupVethPair(t, "vr0b")
+	c := setupVethPair(t, "vr0c")
+
+	reflector, err := NewReflector([]string{a.Name, b.Name}, false, true, false, nil)
+	if err != nil {
+		t.Fatalf("NewReflector: %v", err)
+	}
+	if err := reflector.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(reflector.Stop)
+
+	// No-op reload: the interface set is unchanged.
+	if err := reflector.Reload(&Config{Interfaces: []string{a.Name, b.Name}}); err != nil {
+		t.Fatalf("no-op Reload: %v", err)
+	}
+	if _, ok := reflector.ifaceByIdx[a.Index]; !ok {
+		t.Errorf("expected %s to still be present after no-op reload", a.Name)
+	}
+	if _, ok := reflector.ifaceByIdx[b.Index]; !ok {
+		t.Errorf("expected %s to still be present after no-op reload", b.Name)
+	}
+
+	// Swap b for c: b should be dropped, c should be added, a untouched.
+	if err := reflector.Reload(&Config{Interfaces: []string{a.Name, c.Name}}); err != nil {
+		t.Fatalf("add/remove Reload: %v", err)
+	}
+	if _, ok := reflector.ifaceByIdx[a.Index]; !ok {
+		t.Errorf("expected %s to remain after swap reload", a.Name)
+	}
+	if _, ok := reflector.ifaceByIdx[b.Index]; ok {
+		t.Errorf("expected %s to be removed after swap reload", b.Name)
+	}
+	if _, ok := reflector.ifaceByIdx[c.Index]; !ok {
+		t.Errorf("expected %s to be added after swap reload", c.Name)
+	}
+}
+
+func TestDiffInterfacesAllNew(t *testing.T) {
+	eth0 := &net.Interface{Index: 1, Name: "eth0"}
+	wlan0 := &net.Interface{Index: 2, Name: "wlan0"}
+
+	added, removed := diffInterfaces(nil, []*net.Interface{eth0, wlan0})
+
+	if len(added) != 2 {
+		t.Errorf("expected both interfaces to be added, got %v", namesOf(added))
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected nothing removed, got %v", namesOf(removed))
+	}
+}
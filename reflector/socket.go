@@ -0,0 +1,113 @@
+package reflector
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// family identifies which IP address family a socket or packet belongs to.
+type family int
+
+const (
+	familyIPv4 family = iota
+	familyIPv6
+)
+
+func (f family) String() string {
+	switch f {
+	case familyIPv4:
+		return "ipv4"
+	case familyIPv6:
+		return "ipv6"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	mdnsIPv4Addr = net.ParseIP("224.0.0.251")
+	mdnsIPv6Addr = net.ParseIP("ff02::fb")
+)
+
+// connKey identifies a source socket by interface and address family.
+type connKey struct {
+	iface  string
+	family family
+}
+
+func (k connKey) String() string {
+	return fmt.Sprintf("%s/%s", k.iface, k.family)
+}
+
+// openIPv4 opens a single IPv4 UDP socket bound to the mDNS port, joins the
+// multicast group on every configured interface, and arranges for inbound
+// packets to be tagged with the interface they actually arrived on.
+func (r *Reflector) openIPv4() (*ipv4.PacketConn, error) {
+	conn, err := net.ListenPacket("udp4", fmt.Sprintf(":%d", mdnsPort))
+	if err != nil {
+		return nil, err
+	}
+
+	pc := ipv4.NewPacketConn(conn)
+
+	if err := pc.SetControlMessage(ipv4.FlagInterface|ipv4.FlagTTL, true); err != nil {
+		pc.Close()
+		return nil, fmt.Errorf("failed to enable IPv4 control messages: %w", err)
+	}
+
+	if err := pc.SetMulticastTTL(255); err != nil {
+		pc.Close()
+		return nil, fmt.Errorf("failed to set multicast TTL: %w", err)
+	}
+
+	for _, iface := range r.interfaces {
+		if err := pc.JoinGroup(iface, &net.UDPAddr{IP: mdnsIPv4Addr}); err != nil {
+			pc.Close()
+			return nil, fmt.Errorf("failed to join IPv4 group on %s: %w", iface.Name, err)
+		}
+	}
+
+	return pc, nil
+}
+
+// openIPv6 is the IPv6 counterpart of openIPv4, joining ff02::fb on every
+// configured interface.
+func (r *Reflector) openIPv6() (*ipv6.PacketConn, error) {
+	conn, err := net.ListenPacket("udp6", fmt.Sprintf("[::]:%d", mdnsPort))
+	if err != nil {
+		return nil, err
+	}
+
+	pc := ipv6.NewPacketConn(conn)
+
+	if err := pc.SetControlMessage(ipv6.FlagInterface|ipv6.FlagHopLimit, true); err != nil {
+		pc.Close()
+		return nil, fmt.Errorf("failed to enable IPv6 control messages: %w", err)
+	}
+
+	if err := pc.SetMulticastHopLimit(255); err != nil {
+		pc.Close()
+		return nil, fmt.Errorf("failed to set multicast hop limit: %w", err)
+	}
+
+	for _, iface := range r.interfaces {
+		if err := pc.JoinGroup(iface, &net.UDPAddr{IP: mdnsIPv6Addr}); err != nil {
+			pc.Close()
+			return nil, fmt.Errorf("failed to join IPv6 group on %s: %w", iface.Name, err)
+		}
+	}
+
+	return pc, nil
+}
+
+func destAddr(f family) *net.UDPAddr {
+	switch f {
+	case familyIPv6:
+		return &net.UDPAddr{IP: mdnsIPv6Addr, Port: mdnsPort}
+	default:
+		return &net.UDPAddr{IP: mdnsIPv4Addr, Port: mdnsPort}
+	}
+}
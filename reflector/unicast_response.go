@@ -0,0 +1,43 @@
+package reflector
+
+// Unicast-response (QU bit) handling design
+//
+// RFC 6762 section 5.4 lets a querier set the QU bit on a question to ask
+// for a unicast reply instead of a multicast one, and mDNS Reflector does
+// not yet support that path: today we only ever forward multicast traffic
+// between interfaces, so a unicast reply sent directly to a querier on a
+// far interface never reaches the interface the original query came from.
+//
+// The design settled on for a future request:
+//
+//  1. reflect() already forwards a QU query onto the far interface
+//     unmodified: payloads are never mutated on the wire path (with filter
+//     rules set they are parsed, but the original bytes are sent).
+//     No change is needed there.
+//
+//  2. Add a per-interface unicast UDP listener on port 5353, bound to each
+//     of that interface's addresses (the same addresses tracked in
+//     ifaceUnicastIP). This is a plain net.ListenUDP("udp4"/"udp6", ...)
+//     per address, separate from the pc4/pc6 multicast sockets, since a
+//     unicast reply is delivered to the querier's own address rather than
+//     to the multicast group.
+//
+//  3. When a unicast listener receives a packet, treat its arrival
+//     interface as the "far" side (the one that answered) and the
+//     original querier's interface as the reflection target: re-multicast
+//     the payload on the source interface's mdns group, going through the
+//     same checks as multicast traffic (dedup in handlePacket, filtering in
+//     reflect()) so the existing loop protection and filtering rules keep
+//     applying.
+//
+//  4. The far side sends its unicast reply to the reflector's own address
+//     on the far interface (the source of the reflected query), not to the
+//     original querier, so the listener that receives it identifies only
+//     the far interface. Finding the querier's interface therefore needs
+//     correlation state recorded when the QU query is reflected, such as
+//     the DNS message ID mapped to the source interface.
+//
+// This needs its own request to land: it adds a new socket type and a new
+// receive loop per interface, which is a large enough surface to want
+// isolated review and tests rather than folding it into the TTL/filtering
+// work here.